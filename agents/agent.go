@@ -0,0 +1,91 @@
+// Package agents loads named personas — a system prompt, an allowed tool
+// set, and pinned context files — so a chat session can be restricted to
+// "what this session can use" separately from "what tools exist".
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/JasonCRodriguez/owllama/provider"
+)
+
+// Agent is a reusable persona: a system prompt, the tools it may call, and
+// files that are always attached as context.
+type Agent struct {
+	Name           string   `yaml:"name"`
+	SystemPrompt   string   `yaml:"system_prompt"`
+	AllowedTools   []string `yaml:"allowed_tools"`
+	PinnedFiles    []string `yaml:"pinned_files"`
+	ShellAllowlist []string `yaml:"shell_allowlist"`
+}
+
+// Load reads the agent definition named name from
+// ~/.config/owllama/agents/<name>.yaml.
+func Load(name string) (*Agent, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, name+".yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading agent %q: %w", name, err)
+	}
+	var a Agent
+	if err := yaml.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("parsing agent %q: %w", name, err)
+	}
+	if a.Name == "" {
+		a.Name = name
+	}
+	return &a, nil
+}
+
+// CanUseTool reports whether the agent's whitelist permits calling tool.
+// An agent with no whitelist may call any tool.
+func (a *Agent) CanUseTool(tool string) bool {
+	if len(a.AllowedTools) == 0 {
+		return true
+	}
+	for _, t := range a.AllowedTools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// SystemMessage returns the agent's system prompt as a role:system message,
+// ready to prepend to a chat.
+func (a *Agent) SystemMessage() provider.Message {
+	return provider.Message{Role: "system", Content: a.SystemPrompt}
+}
+
+// PinnedContext reads the agent's pinned files and returns each as a
+// role:system message prefixed with its path, for use as RAG context.
+func (a *Agent) PinnedContext() ([]provider.Message, error) {
+	var messages []provider.Message
+	for _, path := range a.PinnedFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading pinned file %q: %w", path, err)
+		}
+		messages = append(messages, provider.Message{
+			Role:    "system",
+			Content: fmt.Sprintf("Context from %s:\n%s", path, string(data)),
+		})
+	}
+	return messages, nil
+}
+
+func configDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "owllama", "agents"), nil
+}
@@ -0,0 +1,42 @@
+package historystore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Export renders session in the given format ("json" or "md") for
+// portability outside the history database.
+func Export(session ChatSession, format string) (string, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(session, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case "md":
+		return exportMarkdown(session), nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (want json or md)", format)
+	}
+}
+
+func exportMarkdown(session ChatSession) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Session %s\n\n", session.SessionKey)
+	for _, msg := range session.Messages {
+		role := msg.Role
+		if len(role) > 0 {
+			role = strings.ToUpper(role[:1]) + role[1:]
+		}
+		if msg.ToolCall != nil {
+			argsJSON, _ := json.Marshal(msg.ToolCall.Arguments)
+			fmt.Fprintf(&b, "**%s** called `%s(%s)` -> %s\n\n", role, msg.ToolCall.Name, string(argsJSON), msg.ToolCall.Result)
+			continue
+		}
+		fmt.Fprintf(&b, "**%s**: %s\n\n", role, msg.Content)
+	}
+	return strings.TrimSpace(b.String())
+}
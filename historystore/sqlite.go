@@ -0,0 +1,311 @@
+package historystore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS sessions (
+		key          TEXT PRIMARY KEY,
+		provider     TEXT,
+		model        TEXT,
+		agent        TEXT,
+		current_leaf TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS messages (
+		id          TEXT PRIMARY KEY,
+		session_key TEXT NOT NULL REFERENCES sessions(key),
+		parent_id   TEXT,
+		seq         INTEGER NOT NULL,
+		role        TEXT NOT NULL,
+		content     TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS messages_session_idx ON messages(session_key)`,
+	`CREATE TABLE IF NOT EXISTS tool_calls (
+		message_id TEXT PRIMARY KEY REFERENCES messages(id),
+		name       TEXT NOT NULL,
+		arguments  TEXT NOT NULL,
+		result     TEXT NOT NULL
+	)`,
+	`CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+		message_id UNINDEXED,
+		session_key UNINDEXED,
+		content
+	)`,
+}
+
+// Store is a SQLite-backed ChatHistory.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (creating if necessary) the history database at
+// ~/.local/share/owllama/history.db and ensures its schema exists.
+func Open() (*Store, error) {
+	path, err := dbPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating history directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening history database: %w", err)
+	}
+	store := &Store{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func dbPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "owllama", "history.db"), nil
+}
+
+func (s *Store) migrate() error {
+	for _, stmt := range schema {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("migrating history database: %w", err)
+		}
+	}
+	// sessions.agent was added after the initial schema; CREATE TABLE IF NOT
+	// EXISTS above is a no-op against a database from before that, so add
+	// the column by hand for upgraders, ignoring the "already exists" error
+	// on databases that already have it.
+	if _, err := s.db.Exec(`ALTER TABLE sessions ADD COLUMN agent TEXT`); err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return fmt.Errorf("migrating history database: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveSession upserts session's row and replaces its messages and tool
+// calls wholesale. This only touches the one session being saved, not the
+// whole history, so a turn's cost no longer grows with the number of past
+// sessions.
+func (s *Store) SaveSession(session ChatSession) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO sessions (key, provider, model, agent, current_leaf) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET provider = excluded.provider, model = excluded.model, agent = excluded.agent, current_leaf = excluded.current_leaf
+	`, session.SessionKey, session.Provider, session.Model, session.AgentName, session.CurrentLeaf); err != nil {
+		return fmt.Errorf("saving session: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM tool_calls WHERE message_id IN (SELECT id FROM messages WHERE session_key = ?)`, session.SessionKey); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM messages_fts WHERE session_key = ?`, session.SessionKey); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM messages WHERE session_key = ?`, session.SessionKey); err != nil {
+		return err
+	}
+
+	for i, msg := range session.Messages {
+		if _, err := tx.Exec(`INSERT INTO messages (id, session_key, parent_id, seq, role, content) VALUES (?, ?, ?, ?, ?, ?)`,
+			msg.ID, session.SessionKey, msg.ParentID, i, msg.Role, msg.Content); err != nil {
+			return fmt.Errorf("saving message %s: %w", msg.ID, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO messages_fts (message_id, session_key, content) VALUES (?, ?, ?)`,
+			msg.ID, session.SessionKey, msg.Content); err != nil {
+			return fmt.Errorf("indexing message %s: %w", msg.ID, err)
+		}
+		if msg.ToolCall != nil {
+			argsJSON, err := json.Marshal(msg.ToolCall.Arguments)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`INSERT INTO tool_calls (message_id, name, arguments, result) VALUES (?, ?, ?, ?)`,
+				msg.ID, msg.ToolCall.Name, string(argsJSON), msg.ToolCall.Result); err != nil {
+				return fmt.Errorf("saving tool call for %s: %w", msg.ID, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LoadSession reads a single session by key, including its full message
+// tree.
+func (s *Store) LoadSession(key string) (*ChatSession, bool, error) {
+	var session ChatSession
+	session.SessionKey = key
+	var agentName sql.NullString
+	row := s.db.QueryRow(`SELECT provider, model, agent, current_leaf FROM sessions WHERE key = ?`, key)
+	if err := row.Scan(&session.Provider, &session.Model, &agentName, &session.CurrentLeaf); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	session.AgentName = agentName.String
+	messages, err := s.loadMessages(key)
+	if err != nil {
+		return nil, false, err
+	}
+	session.Messages = messages
+	return &session, true, nil
+}
+
+// LoadAll reads every session's full message tree.
+func (s *Store) LoadAll() (ChatHistory, error) {
+	rows, err := s.db.Query(`SELECT key FROM sessions`)
+	if err != nil {
+		return ChatHistory{}, err
+	}
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return ChatHistory{}, err
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return ChatHistory{}, err
+	}
+
+	var history ChatHistory
+	for _, key := range keys {
+		session, ok, err := s.LoadSession(key)
+		if err != nil {
+			return ChatHistory{}, err
+		}
+		if ok {
+			history.Sessions = append(history.Sessions, *session)
+		}
+	}
+	return history, nil
+}
+
+func (s *Store) loadMessages(key string) ([]ChatMessage, error) {
+	rows, err := s.db.Query(`SELECT id, parent_id, role, content FROM messages WHERE session_key = ? ORDER BY seq`, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []ChatMessage
+	for rows.Next() {
+		var msg ChatMessage
+		var parentID sql.NullString
+		if err := rows.Scan(&msg.ID, &parentID, &msg.Role, &msg.Content); err != nil {
+			return nil, err
+		}
+		msg.ParentID = parentID.String
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	toolCalls, err := s.loadToolCalls(key)
+	if err != nil {
+		return nil, err
+	}
+	for i := range messages {
+		if tc, ok := toolCalls[messages[i].ID]; ok {
+			messages[i].ToolCall = &tc
+		}
+	}
+
+	rebuildChildren(messages)
+	return messages, nil
+}
+
+func (s *Store) loadToolCalls(key string) (map[string]ToolCallRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT tc.message_id, tc.name, tc.arguments, tc.result
+		FROM tool_calls tc JOIN messages m ON m.id = tc.message_id
+		WHERE m.session_key = ?
+	`, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]ToolCallRecord)
+	for rows.Next() {
+		var id, name, argsJSON, result string
+		if err := rows.Scan(&id, &name, &argsJSON, &result); err != nil {
+			return nil, err
+		}
+		var args map[string]any
+		if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+			return nil, err
+		}
+		out[id] = ToolCallRecord{Name: name, Arguments: args, Result: result}
+	}
+	return out, rows.Err()
+}
+
+// rebuildChildren populates each message's Children from the other
+// messages' ParentID, since the schema stores the parent pointer only.
+func rebuildChildren(messages []ChatMessage) {
+	byID := make(map[string]*ChatMessage, len(messages))
+	for i := range messages {
+		byID[messages[i].ID] = &messages[i]
+	}
+	for i := range messages {
+		if parent, ok := byID[messages[i].ParentID]; ok {
+			parent.Children = append(parent.Children, messages[i].ID)
+		}
+	}
+}
+
+// SearchHit is one message matching a full-text search query.
+type SearchHit struct {
+	SessionKey string
+	MessageID  string
+	Role       string
+	Snippet    string
+}
+
+// Search runs a full-text query across every session's message content.
+func (s *Store) Search(query string) ([]SearchHit, error) {
+	rows, err := s.db.Query(`
+		SELECT m.session_key, m.id, m.role, snippet(messages_fts, 2, '[', ']', '...', 8)
+		FROM messages_fts JOIN messages m ON m.id = messages_fts.message_id
+		WHERE messages_fts MATCH ?
+		ORDER BY rank
+	`, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var hit SearchHit
+		if err := rows.Scan(&hit.SessionKey, &hit.MessageID, &hit.Role, &hit.Snippet); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
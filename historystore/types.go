@@ -0,0 +1,115 @@
+// Package historystore persists chat sessions — and the branching tree of
+// messages within each one — to a local SQLite database, and provides
+// full-text search and export across them.
+package historystore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// ChatMessage represents a single message in a chat session. Messages form
+// a tree rather than a flat line: ParentID points at the message this one
+// was sent in reply to ("" for the first message of a session), and
+// Children lists every reply ID, of which there may be more than one once
+// a branch has been created with "history reply" or "history edit".
+type ChatMessage struct {
+	ID       string          `json:"id"`
+	ParentID string          `json:"parent_id,omitempty"`
+	Children []string        `json:"children,omitempty"`
+	Role     string          `json:"role"`
+	Content  string          `json:"content"`
+	ToolCall *ToolCallRecord `json:"tool_call,omitempty"`
+}
+
+// ToolCallRecord persists a single tool call and its result so
+// "history view" can replay it.
+type ToolCallRecord struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+	Result    string         `json:"result"`
+}
+
+// ChatSession represents a chat session. Provider and Model record which
+// backend the session was talking to, and AgentName (if any) which agent
+// persona, so "history reply"/"history edit" can resume a conversation
+// without the caller having to specify them again. CurrentLeaf is the
+// message ID the session last appended to; AddMessage keeps it up to date
+// as the active tip of the conversation.
+type ChatSession struct {
+	SessionKey  string        `json:"session_key"`
+	Provider    string        `json:"provider"`
+	Model       string        `json:"model"`
+	AgentName   string        `json:"agent,omitempty"`
+	Messages    []ChatMessage `json:"messages"`
+	CurrentLeaf string        `json:"current_leaf,omitempty"`
+}
+
+// AddMessage appends msg as a child of the session's current leaf, assigns
+// it a fresh ID, links it into its parent's Children, and advances
+// CurrentLeaf to it.
+func (s *ChatSession) AddMessage(msg ChatMessage) ChatMessage {
+	msg.ID = NewID()
+	msg.ParentID = s.CurrentLeaf
+	s.Messages = append(s.Messages, msg)
+	if parent, ok := s.FindMessage(msg.ParentID); ok {
+		parent.Children = append(parent.Children, msg.ID)
+	}
+	s.CurrentLeaf = msg.ID
+	return msg
+}
+
+// FindMessage looks up a message by ID, returning a pointer into
+// s.Messages so callers can mutate it in place.
+func (s *ChatSession) FindMessage(id string) (*ChatMessage, bool) {
+	if id == "" {
+		return nil, false
+	}
+	for i := range s.Messages {
+		if s.Messages[i].ID == id {
+			return &s.Messages[i], true
+		}
+	}
+	return nil, false
+}
+
+// Ancestors returns the path from the session's root message down to id,
+// inclusive, in conversation order.
+func (s *ChatSession) Ancestors(id string) []ChatMessage {
+	var chain []ChatMessage
+	for {
+		msg, ok := s.FindMessage(id)
+		if !ok {
+			break
+		}
+		chain = append([]ChatMessage{*msg}, chain...)
+		id = msg.ParentID
+	}
+	return chain
+}
+
+// ChatHistory is a collection of chat sessions.
+type ChatHistory struct {
+	Sessions []ChatSession
+}
+
+// FindSession looks up a session by key, returning a pointer into
+// h.Sessions so callers can mutate it in place before saving.
+func (h *ChatHistory) FindSession(key string) (*ChatSession, bool) {
+	for i := range h.Sessions {
+		if h.Sessions[i].SessionKey == key {
+			return &h.Sessions[i], true
+		}
+	}
+	return nil, false
+}
+
+// NewID returns a random hex identifier, used for both session keys and
+// message IDs. 16 bytes (128 bits) keeps collisions implausible even once
+// the messages table's single global ID namespace (historystore/sqlite.go)
+// has accumulated millions of rows across every session.
+func NewID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
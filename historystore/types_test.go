@@ -0,0 +1,51 @@
+package historystore
+
+import "testing"
+
+func TestAddMessageLinksParentAndAdvancesLeaf(t *testing.T) {
+	var session ChatSession
+	first := session.AddMessage(ChatMessage{Role: "user", Content: "hi"})
+	second := session.AddMessage(ChatMessage{Role: "ollama", Content: "hello"})
+
+	if second.ParentID != first.ID {
+		t.Errorf("second.ParentID = %q, want %q", second.ParentID, first.ID)
+	}
+	if session.CurrentLeaf != second.ID {
+		t.Errorf("CurrentLeaf = %q, want %q", session.CurrentLeaf, second.ID)
+	}
+	parent, ok := session.FindMessage(first.ID)
+	if !ok {
+		t.Fatalf("FindMessage(%q) not found", first.ID)
+	}
+	if len(parent.Children) != 1 || parent.Children[0] != second.ID {
+		t.Errorf("parent.Children = %v, want [%q]", parent.Children, second.ID)
+	}
+}
+
+func TestAncestorsFollowsBranchToRoot(t *testing.T) {
+	var session ChatSession
+	root := session.AddMessage(ChatMessage{Role: "user", Content: "root"})
+	mid := session.AddMessage(ChatMessage{Role: "ollama", Content: "mid"})
+
+	// Branch: a second reply to root, independent of mid.
+	session.CurrentLeaf = root.ID
+	branch := session.AddMessage(ChatMessage{Role: "ollama", Content: "branch"})
+
+	chain := session.Ancestors(branch.ID)
+	if len(chain) != 2 || chain[0].ID != root.ID || chain[1].ID != branch.ID {
+		t.Errorf("Ancestors(branch) = %v, want [root, branch]", chain)
+	}
+	if mid.ID == branch.ID {
+		t.Fatal("branch should not reuse mid's ID")
+	}
+}
+
+func TestNewIDIsUniqueAndWide(t *testing.T) {
+	a, b := NewID(), NewID()
+	if a == b {
+		t.Fatalf("NewID produced a duplicate: %q", a)
+	}
+	if len(a) != 32 { // 16 random bytes, hex-encoded
+		t.Errorf("NewID length = %d, want 32", len(a))
+	}
+}
@@ -2,36 +2,100 @@ package main
 
 import (
 	"bufio"
-	"bytes"
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"strings"
 
 	markdown "github.com/MichaelMure/go-term-markdown"
 	"github.com/ollama/ollama/api"
-)
 
-const (
-	ollamaAPIURL = "http://localhost:11434/api/chat"
-	historyFile  = "owllama_chat_history.json"
+	"github.com/JasonCRodriguez/owllama/agents"
+	"github.com/JasonCRodriguez/owllama/historystore"
+	"github.com/JasonCRodriguez/owllama/provider"
+	"github.com/JasonCRodriguez/owllama/search"
+	"github.com/JasonCRodriguez/owllama/toolbox"
 )
 
 func printUsage() {
 	fmt.Println("Usage: owllama <command> [args]")
 	fmt.Println("Commands:")
 	fmt.Println("  list")
-	fmt.Println("  generate <model> <prompt>")
+	fmt.Println("  generate [--provider <name>] [-a/--agent <name>] <model> <prompt>")
 	fmt.Println("  version")
 	fmt.Println("  help")
-	fmt.Println("  chat <model>")
-	fmt.Println("  history <list|view> [key]")
+	fmt.Println("  chat [--provider <name>] [-a/--agent <name>] <model>")
+	fmt.Println("  history <list|view|branches> [key]")
+	fmt.Println("  history reply <session> <msgID>")
+	fmt.Println("  history edit <session> <msgID>")
+	fmt.Println("  history search <query>")
+	fmt.Println("  history export <session> [--format json|md]")
+	fmt.Println()
+	fmt.Println("<model> may also be prefixed with a provider, e.g. openai:gpt-4o")
+}
+
+// resolveProvider picks a backend from a "provider:model" string or an
+// explicit --provider flag (flag wins), falling back to config.yaml's
+// default_provider and finally to ollama, then loads that provider's
+// settings from ~/.config/owllama/config.yaml.
+func resolveProvider(modelArg, flagProvider string) (provider.ChatCompletionProvider, string, string, error) {
+	name, model := provider.ParseModelString(modelArg)
+	if flagProvider != "" {
+		name = flagProvider
+	}
+	cfg, err := provider.LoadConfig()
+	if err != nil {
+		return nil, "", "", err
+	}
+	if name == "" {
+		name = cfg.DefaultProvider
+	}
+	if name == "" {
+		name = "ollama"
+	}
+	p, err := provider.New(name, cfg.Get(name))
+	if err != nil {
+		return nil, "", "", err
+	}
+	if model == "" {
+		model = cfg.Get(name).DefaultModel
+	}
+	return p, name, model, nil
+}
+
+// extractProviderFlag pulls a "--provider <name>" (or "--provider=<name>")
+// pair out of args, returning the remaining positional args and the value.
+func extractProviderFlag(args []string) (remaining []string, value string) {
+	return extractFlag(args, "--provider", "")
+}
+
+// extractAgentFlag pulls a "-a/--agent <name>" (or "--agent=<name>") pair
+// out of args, returning the remaining positional args and the value.
+func extractAgentFlag(args []string) (remaining []string, value string) {
+	return extractFlag(args, "--agent", "-a")
+}
+
+// extractFlag pulls a "--long <value>"/"--long=<value>" (optionally also
+// "-short <value>") pair out of args, returning the remaining positional
+// args and the value.
+func extractFlag(args []string, long, short string) (remaining []string, value string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == long || (short != "" && arg == short)) && i+1 < len(args):
+			value = args[i+1]
+			i++
+		case strings.HasPrefix(arg, long+"="):
+			value = strings.TrimPrefix(arg, long+"=")
+		default:
+			remaining = append(remaining, arg)
+		}
+	}
+	return remaining, value
 }
 
 func main() {
@@ -80,28 +144,76 @@ func handleList(ctx context.Context, client *api.Client) {
 }
 
 func handleGenerate(ctx context.Context, client *api.Client) {
-	if len(os.Args) < 4 {
-		fmt.Println("Usage: owllama generate <model> <prompt>")
+	args, providerFlag := extractProviderFlag(os.Args[2:])
+	args, agentName := extractAgentFlag(args)
+	if len(args) < 2 {
+		fmt.Println("Usage: owllama generate [--provider <name>] [-a/--agent <name>] <model> <prompt>")
 		os.Exit(1)
 	}
-	model := os.Args[2]
-	prompt := os.Args[3]
-	req := &api.GenerateRequest{
-		Model:  model,
-		Prompt: prompt,
-		Stream: nil, // disables streaming, returns full response
+	modelArg, prompt := args[0], args[1]
+
+	providerName, _ := provider.ParseModelString(modelArg)
+	if providerFlag == "" && providerName == "" && agentName == "" {
+		// No provider or agent requested: keep the original Ollama-native
+		// generate path, which streams through the ollama SDK's own client.
+		req := &api.GenerateRequest{
+			Model:  modelArg,
+			Prompt: prompt,
+			Stream: nil, // nil keeps Ollama's default, which streams the response
+		}
+		err := client.Generate(ctx, req, func(resp api.GenerateResponse) error {
+			fmt.Print(resp.Response)
+			if resp.Done {
+				fmt.Println()
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating response: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	p, _, model, err := resolveProvider(modelArg, providerFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving provider: %v\n", err)
+		os.Exit(1)
 	}
-	err := client.Generate(ctx, req, func(resp api.GenerateResponse) error {
-		fmt.Print(resp.Response)
-		if resp.Done {
-			fmt.Println()
+	var agent *agents.Agent
+	if agentName != "" {
+		agent, err = agents.Load(agentName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading agent: %v\n", err)
+			os.Exit(1)
 		}
-		return nil
-	})
+	}
+	messages, err := agentMessages(agent)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading agent context: %v\n", err)
+		os.Exit(1)
+	}
+	messages = append(messages, provider.Message{Role: "user", Content: prompt})
+	text, _, err := p.Chat(ctx, model, messages, nil)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error generating response: %v\n", err)
 		os.Exit(1)
 	}
+	fmt.Println(text)
+}
+
+// agentMessages returns agent's system prompt and pinned-file context as
+// the opening messages of a chat. A nil agent yields no messages.
+func agentMessages(agent *agents.Agent) ([]provider.Message, error) {
+	if agent == nil {
+		return nil, nil
+	}
+	messages := []provider.Message{agent.SystemMessage()}
+	pinned, err := agent.PinnedContext()
+	if err != nil {
+		return nil, err
+	}
+	return append(messages, pinned...), nil
 }
 
 func handleVersion(ctx context.Context, client *api.Client) {
@@ -113,39 +225,90 @@ func handleVersion(ctx context.Context, client *api.Client) {
 	fmt.Println(ver)
 }
 
-func handleChat(_ context.Context, _ *api.Client) {
+func handleChat(ctx context.Context, _ *api.Client) {
 	reader := bufio.NewReader(os.Stdin)
-	model := "gemma3"
-	if len(os.Args) >= 3 {
-		model = os.Args[2]
+	args, providerFlag := extractProviderFlag(os.Args[2:])
+	args, agentName := extractAgentFlag(args)
+	modelArg := "gemma3"
+	if len(args) >= 1 {
+		modelArg = args[0]
 	}
-	fullPrompt, err := buildPrompt(reader)
+	chatProvider, providerName, model, err := resolveProvider(modelArg, providerFlag)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Prompt error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error resolving provider: %v\n", err)
 		return
 	}
+	var agent *agents.Agent
+	if agentName != "" {
+		agent, err = agents.Load(agentName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading agent: %v\n", err)
+			return
+		}
+	}
+	messages, err := agentMessages(agent)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading agent context: %v\n", err)
+		return
+	}
+	var shellAllowlist []string
+	if agent != nil {
+		shellAllowlist = agent.ShellAllowlist
+	}
+	tb := toolbox.Default(shellAllowlist)
+	toolSpecs := tb.Filtered(nil)
+	if agent != nil {
+		toolSpecs = tb.Filtered(agent.AllowedTools)
+	}
+	autoApprove := false
+
+	// An active agent is a reusable persona, so it replaces the one-shot
+	// prompt-building wizard: just start the conversation.
+	var fullPrompt string
+	if agentName == "" {
+		fullPrompt, err = buildPrompt(reader)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Prompt error: %v\n", err)
+			return
+		}
+	} else {
+		fmt.Print("\033[1;36mYou: \033[0m")
+		fullPrompt, err = reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Prompt error: %v\n", err)
+			return
+		}
+		fullPrompt = strings.TrimSpace(fullPrompt)
+	}
 
-	sessionKey := generateSessionKey()
-	chatHistory := loadChatHistory()
-	session := ChatSession{
-		SessionKey: sessionKey,
-		Messages:   []ChatMessage{},
+	session := historystore.ChatSession{
+		SessionKey: historystore.NewID(),
+		Provider:   providerName,
+		Model:      model,
+		AgentName:  agentName,
+		Messages:   []historystore.ChatMessage{},
 	}
-	var messages []map[string]string
-	messages = append(messages, map[string]string{"role": "user", "content": fullPrompt})
+	messages = append(messages, provider.Message{Role: "user", Content: fullPrompt})
 
-	responseText, err := ollamaChatAPI(model, messages)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error contacting Ollama API: %v\n", err)
+	fmt.Println("\033[1;36mUser:\033[0m")
+	printMarkdown(fullPrompt)
+	fmt.Println("\033[1;32mOllama:\033[0m")
+	responseText, messages, toolRecords, streamed, err := runToolLoop(ctx, chatProvider, model, messages, tb, toolSpecs, reader, &autoApprove, agent)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "Error contacting %s: %v\n", modelArg, err)
 	} else {
-		printText := filterQwenThink(model, responseText)
-		fmt.Println("\033[1;36mUser:\033[0m")
-		printMarkdown(fullPrompt)
-		fmt.Println("\033[1;32mOllama:\033[0m")
-		printMarkdown(printText)
-		messages = append(messages, map[string]string{"role": "assistant", "content": responseText})
-		session.Messages = append(session.Messages, ChatMessage{Role: "user", Content: fullPrompt})
-		session.Messages = append(session.Messages, ChatMessage{Role: "ollama", Content: responseText})
+		if err != nil {
+			fmt.Println("\n[Interrupted] Saving partial response to history.")
+		}
+		if !streamed {
+			printMarkdown(filterQwenThink(model, responseText))
+		}
+		messages = append(messages, provider.Message{Role: "assistant", Content: responseText})
+		session.AddMessage(historystore.ChatMessage{Role: "user", Content: fullPrompt})
+		for _, tr := range toolRecords {
+			session.AddMessage(tr)
+		}
+		session.AddMessage(historystore.ChatMessage{Role: "ollama", Content: responseText})
 	}
 	fmt.Println("\nType /exit to quit. Type /clear to reset context.")
 
@@ -201,13 +364,13 @@ func handleChat(_ context.Context, _ *api.Client) {
 				continue
 			}
 			fmt.Println("Searching the internet for:", query)
-			result, err := searchInternet(query)
+			result, err := runSearch(ctx, query)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Search error: %v\n", err)
 				continue
 			}
 			fmt.Println("Search result:", result)
-			messages = append(messages, map[string]string{"role": "assistant", "content": result})
+			messages = append(messages, provider.Message{Role: "system", Content: result})
 			continue
 		}
 		if prompt == "/exit" {
@@ -222,20 +385,30 @@ func handleChat(_ context.Context, _ *api.Client) {
 		if prompt == "" {
 			continue
 		}
-		messages = append(messages, map[string]string{"role": "user", "content": prompt})
-		responseText, err := ollamaChatAPI(model, messages)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error contacting Ollama API: %v\n", err)
-			continue
-		}
-		printText := filterQwenThink(model, responseText)
+		messages = append(messages, provider.Message{Role: "user", Content: prompt})
 		fmt.Println("\033[1;36mYou:\033[0m")
 		printMarkdown(prompt)
 		fmt.Println("\033[1;32mOllama:\033[0m")
-		printMarkdown(printText)
-		messages = append(messages, map[string]string{"role": "assistant", "content": responseText})
-		session.Messages = append(session.Messages, ChatMessage{Role: "user", Content: prompt})
-		session.Messages = append(session.Messages, ChatMessage{Role: "ollama", Content: responseText})
+		var toolRecords []historystore.ChatMessage
+		var responseText string
+		var streamed bool
+		responseText, messages, toolRecords, streamed, err = runToolLoop(ctx, chatProvider, model, messages, tb, toolSpecs, reader, &autoApprove, agent)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(os.Stderr, "Error contacting %s: %v\n", modelArg, err)
+			continue
+		}
+		if err != nil {
+			fmt.Println("\n[Interrupted] Saving partial response to history.")
+		}
+		if !streamed {
+			printMarkdown(filterQwenThink(model, responseText))
+		}
+		messages = append(messages, provider.Message{Role: "assistant", Content: responseText})
+		session.AddMessage(historystore.ChatMessage{Role: "user", Content: prompt})
+		for _, tr := range toolRecords {
+			session.AddMessage(tr)
+		}
+		session.AddMessage(historystore.ChatMessage{Role: "ollama", Content: responseText})
 	}
 	userInputFound := false
 	for _, msg := range session.Messages {
@@ -245,8 +418,7 @@ func handleChat(_ context.Context, _ *api.Client) {
 		}
 	}
 	if userInputFound {
-		chatHistory.Sessions = append(chatHistory.Sessions, session)
-		saveChatHistory(chatHistory)
+		saveSession(session)
 	}
 }
 
@@ -255,9 +427,9 @@ func printMarkdown(md string) {
 	os.Stdout.Write(out)
 }
 
-func handleHistory(_ context.Context, _ *api.Client) {
+func handleHistory(ctx context.Context, _ *api.Client) {
 	if len(os.Args) < 3 {
-		fmt.Println("Usage: owllama history <list|view> [key]")
+		fmt.Println("Usage: owllama history <list|view|branches|reply|edit|search|export> [args]")
 		os.Exit(1)
 	}
 	subcmd := os.Args[2]
@@ -282,27 +454,260 @@ func handleHistory(_ context.Context, _ *api.Client) {
 			fmt.Println("Usage: owllama history view <key>")
 			os.Exit(1)
 		}
-		key := os.Args[3]
-		chatHistory := loadChatHistory()
-		for _, session := range chatHistory.Sessions {
-			if session.SessionKey == key {
-				for _, msg := range session.Messages {
-					role := msg.Role
-					if len(role) > 0 {
-						role = strings.ToUpper(role[:1]) + role[1:]
-					}
-					fmt.Printf("%s: %s\n", role, msg.Content)
-				}
-				return
+		session, ok := requireSession(os.Args[3])
+		if !ok {
+			return
+		}
+		for _, msg := range session.Messages {
+			role := msg.Role
+			if len(role) > 0 {
+				role = strings.ToUpper(role[:1]) + role[1:]
+			}
+			if msg.ToolCall != nil {
+				argsJSON, _ := json.Marshal(msg.ToolCall.Arguments)
+				fmt.Printf("%s: %s(%s) -> %s\n", role, msg.ToolCall.Name, string(argsJSON), msg.ToolCall.Result)
+				continue
 			}
+			fmt.Printf("%s [%s]: %s\n", role, msg.ID, msg.Content)
 		}
-		fmt.Println("Session not found.")
+	case "branches":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: owllama history branches <key>")
+			os.Exit(1)
+		}
+		session, ok := requireSession(os.Args[3])
+		if !ok {
+			return
+		}
+		for _, msg := range session.Messages {
+			if len(msg.Children) > 0 {
+				continue
+			}
+			preview := msg.Content
+			if len(preview) > 40 {
+				preview = preview[:40]
+			}
+			current := ""
+			if msg.ID == session.CurrentLeaf {
+				current = " (current)"
+			}
+			fmt.Printf("%s: %s%s\n", msg.ID, preview, current)
+		}
+	case "reply":
+		if len(os.Args) < 5 {
+			fmt.Println("Usage: owllama history reply <key> <msgID>")
+			os.Exit(1)
+		}
+		replyToMessage(ctx, os.Args[3], os.Args[4])
+	case "edit":
+		if len(os.Args) < 5 {
+			fmt.Println("Usage: owllama history edit <key> <msgID>")
+			os.Exit(1)
+		}
+		editMessage(ctx, os.Args[3], os.Args[4])
+	case "search":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: owllama history search <query>")
+			os.Exit(1)
+		}
+		searchHistory(strings.Join(os.Args[3:], " "))
+	case "export":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: owllama history export <key> [--format json|md]")
+			os.Exit(1)
+		}
+		_, format := extractFlag(os.Args[4:], "--format", "")
+		if format == "" {
+			format = "json"
+		}
+		exportSession(os.Args[3], format)
 	default:
-		fmt.Println("Usage: owllama history <list|view> [key]")
+		fmt.Println("Usage: owllama history <list|view|branches|reply|edit|search|export> [args]")
 		os.Exit(1)
 	}
 }
 
+// requireSession loads a session by key, printing an error and returning
+// ok=false if it isn't found.
+func requireSession(key string) (*historystore.ChatSession, bool) {
+	session, ok := loadSession(key)
+	if !ok {
+		fmt.Println("Session not found.")
+	}
+	return session, ok
+}
+
+// searchHistory runs a full-text search across every session's messages
+// and prints each match.
+func searchHistory(query string) {
+	store, err := historystore.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening history database: %v\n", err)
+		return
+	}
+	defer store.Close()
+	hits, err := store.Search(query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Search error: %v\n", err)
+		return
+	}
+	if len(hits) == 0 {
+		fmt.Println("No matches.")
+		return
+	}
+	for _, hit := range hits {
+		fmt.Printf("%s [%s] %s: %s\n", hit.SessionKey, hit.MessageID, hit.Role, hit.Snippet)
+	}
+}
+
+// exportSession renders a session for portability in the given format.
+func exportSession(key, format string) {
+	session, ok := requireSession(key)
+	if !ok {
+		return
+	}
+	out, err := historystore.Export(*session, format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export error: %v\n", err)
+		return
+	}
+	fmt.Println(out)
+}
+
+// replyToMessage branches a session by prompting the model again from
+// msgID, as if the user had replied to that point in the conversation
+// instead of wherever the session's current leaf happens to be.
+func replyToMessage(ctx context.Context, key, msgID string) {
+	session, ok := requireSession(key)
+	if !ok {
+		return
+	}
+	if _, ok := session.FindMessage(msgID); !ok {
+		fmt.Println("Message not found.")
+		return
+	}
+
+	fmt.Print("\033[1;36mYou: \033[0m")
+	reader := bufio.NewReader(os.Stdin)
+	prompt, _ := reader.ReadString('\n')
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		fmt.Println("Empty prompt, aborting.")
+		return
+	}
+
+	session.CurrentLeaf = msgID
+	userMsg := session.AddMessage(historystore.ChatMessage{Role: "user", Content: prompt})
+	if err := regenerateFrom(ctx, session, userMsg.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "Error contacting %s: %v\n", session.Model, err)
+		return
+	}
+	saveSession(*session)
+}
+
+// editMessage opens msgID's content in $EDITOR, saves the edit in place,
+// and, if the edited message was a user prompt, regenerates the reply to
+// it as a new branch so the original subtree is left untouched.
+func editMessage(ctx context.Context, key, msgID string) {
+	session, ok := requireSession(key)
+	if !ok {
+		return
+	}
+	msg, ok := session.FindMessage(msgID)
+	if !ok {
+		fmt.Println("Message not found.")
+		return
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	tmpfile, err := os.CreateTemp("", "owllama_history_edit_*.md")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not create temp file: %v\n", err)
+		return
+	}
+	tmpfile.WriteString(msg.Content)
+	tmpfile.Close()
+	cmd := exec.Command(editor, tmpfile.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Editor error: %v\n", err)
+		os.Remove(tmpfile.Name())
+		return
+	}
+	content, err := os.ReadFile(tmpfile.Name())
+	os.Remove(tmpfile.Name())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read temp file: %v\n", err)
+		return
+	}
+	msg.Content = strings.TrimSpace(string(content))
+
+	if msg.Role == "user" {
+		session.CurrentLeaf = msg.ID
+		if err := regenerateFrom(ctx, session, msg.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error contacting %s: %v\n", session.Model, err)
+			return
+		}
+	}
+	saveSession(*session)
+}
+
+// regenerateFrom sends session's ancestor chain up to and including fromID
+// to session's provider/model, prints the reply, and appends it as a new
+// child of fromID. If the session was started with an agent, that agent's
+// system prompt and pinned context are reloaded and prepended, since they
+// live in the agent definition rather than in the session's message tree.
+func regenerateFrom(ctx context.Context, session *historystore.ChatSession, fromID string) error {
+	cfg, err := provider.LoadConfig()
+	if err != nil {
+		return err
+	}
+	p, err := provider.New(session.Provider, cfg.Get(session.Provider))
+	if err != nil {
+		return err
+	}
+	var agent *agents.Agent
+	if session.AgentName != "" {
+		agent, err = agents.Load(session.AgentName)
+		if err != nil {
+			return err
+		}
+	}
+	opening, err := agentMessages(agent)
+	if err != nil {
+		return err
+	}
+	messages := append(opening, toProviderMessages(session.Ancestors(fromID))...)
+	text, _, err := p.Chat(ctx, session.Model, messages, nil)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\033[1;32mOllama:\033[0m")
+	printMarkdown(filterQwenThink(session.Model, text))
+	session.AddMessage(historystore.ChatMessage{Role: "ollama", Content: text})
+	return nil
+}
+
+// toProviderMessages converts a session's message chain into the
+// provider-agnostic form Chat expects, mapping the session's "ollama" role
+// to the "assistant" role providers use.
+func toProviderMessages(chain []historystore.ChatMessage) []provider.Message {
+	messages := make([]provider.Message, 0, len(chain))
+	for _, msg := range chain {
+		role := msg.Role
+		if role == "ollama" {
+			role = "assistant"
+		}
+		messages = append(messages, provider.Message{Role: role, Content: msg.Content})
+	}
+	return messages
+}
+
 func buildPrompt(reader *bufio.Reader) (string, error) {
 	fmt.Println("\nWelcome to Owllama Chat!\nLet's build your first prompt step by step for best results.")
 	fmt.Println("Step 1: Who should the AI act as? (Role/Persona)")
@@ -338,50 +743,6 @@ func buildPrompt(reader *bufio.Reader) (string, error) {
 	return fullPrompt, nil
 }
 
-func ollamaChatAPI(model string, messages []map[string]string) (string, error) {
-	bodyMap := struct {
-		Model    string              `json:"model"`
-		Messages []map[string]string `json:"messages"`
-	}{
-		Model:    model,
-		Messages: messages,
-	}
-	bodyBytes, _ := json.Marshal(bodyMap)
-	req, _ := http.NewRequest("POST", ollamaAPIURL, bytes.NewBuffer(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("ollama API error: %s", string(respBody))
-	}
-	var responseText string
-	respScanner := bufio.NewScanner(strings.NewReader(string(respBody)))
-	for respScanner.Scan() {
-		line := respScanner.Text()
-		var apiResp struct {
-			Message struct {
-				Role    string `json:"role"`
-				Content string `json:"content"`
-			} `json:"message"`
-			Done bool `json:"done"`
-		}
-		if err := json.Unmarshal([]byte(line), &apiResp); err != nil {
-			continue
-		}
-		if apiResp.Message.Role == "assistant" {
-			responseText += apiResp.Message.Content
-		}
-		if apiResp.Done {
-			break
-		}
-	}
-	return responseText, nil
-}
-
 func filterQwenThink(model, text string) string {
 	if !strings.Contains(model, "qwen3") {
 		return text
@@ -398,30 +759,50 @@ func filterQwenThink(model, text string) string {
 	return text
 }
 
-func generateSessionKey() string {
-	b := make([]byte, 4)
-	_, _ = rand.Read(b)
-	return hex.EncodeToString(b)
+// loadChatHistory reads every session from the history database. Prefer
+// loadSession when only one session is needed: it avoids reading the rest.
+func loadChatHistory() historystore.ChatHistory {
+	store, err := historystore.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening history database: %v\n", err)
+		return historystore.ChatHistory{}
+	}
+	defer store.Close()
+	history, err := store.LoadAll()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history database: %v\n", err)
+		return historystore.ChatHistory{}
+	}
+	return history
 }
 
-func loadChatHistory() ChatHistory {
-	var chatHistory ChatHistory
-	if f, err := os.Open(historyFile); err == nil {
-		defer f.Close()
-		json.NewDecoder(f).Decode(&chatHistory)
+// loadSession reads a single session by key from the history database.
+func loadSession(key string) (*historystore.ChatSession, bool) {
+	store, err := historystore.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening history database: %v\n", err)
+		return nil, false
 	}
-	return chatHistory
+	defer store.Close()
+	session, ok, err := store.LoadSession(key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history database: %v\n", err)
+		return nil, false
+	}
+	return session, ok
 }
 
-func saveChatHistory(chatHistory ChatHistory) {
-	f, ferr := os.OpenFile(historyFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if ferr == nil {
-		enc := json.NewEncoder(f)
-		enc.SetIndent("", "  ")
-		enc.Encode(chatHistory)
-		f.Close()
-	} else {
-		fmt.Fprintf(os.Stderr, "Error writing to history file: %v\n", ferr)
+// saveSession upserts a single session into the history database, without
+// touching any other session's rows.
+func saveSession(session historystore.ChatSession) {
+	store, err := historystore.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening history database: %v\n", err)
+		return
+	}
+	defer store.Close()
+	if err := store.SaveSession(session); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
 	}
 }
 
@@ -450,55 +831,17 @@ var execCommand = func(name string, arg ...string) *exec.Cmd {
 	return exec.Command(name, arg...)
 }
 
-// ChatMessage represents a single message in a chat session
-type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-// ChatSession represents a chat session
-type ChatSession struct {
-	SessionKey string        `json:"session_key"`
-	Messages   []ChatMessage `json:"messages"`
-}
-
-// ChatHistory is a collection of chat sessions
-type ChatHistory struct {
-	Sessions []ChatSession `json:"sessions"`
-}
-
-// searchInternet performs a simple web search using Wikipedia's API and returns a summary of the top result.
-func searchInternet(query string) (string, error) {
-	// Use Wikipedia's summary API
-	apiURL := "https://en.wikipedia.org/api/rest_v1/page/summary/" + urlQueryEscapeWiki(query)
-	resp, err := http.Get(apiURL)
+// runSearch runs the configured search provider's RAG pipeline for query
+// and returns the top-ranked page chunks, formatted for injection as a
+// role:system context message.
+func runSearch(ctx context.Context, query string) (string, error) {
+	cfg, err := provider.LoadConfig()
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode == 404 {
-		return "No relevant information found.", nil
-	}
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("wikipedia API error: %s", resp.Status)
+	ollamaBaseURL := cfg.Get("ollama").BaseURL
+	if ollamaBaseURL == "" {
+		ollamaBaseURL = "http://localhost:11434"
 	}
-	var data struct {
-		Extract string `json:"extract"`
-		Title   string `json:"title"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", err
-	}
-	if data.Extract != "" {
-		return fmt.Sprintf("%s: %s", data.Title, data.Extract), nil
-	}
-	return "No relevant information found.", nil
-}
-
-// urlQueryEscapeWiki escapes a string for use in a Wikipedia API URL.
-func urlQueryEscapeWiki(s string) string {
-	s = strings.ReplaceAll(s, " ", "_")
-	s = strings.ReplaceAll(s, "\n", "")
-	s = strings.ReplaceAll(s, "\r", "")
-	return s
+	return search.Run(ctx, query, cfg.Search, ollamaBaseURL)
 }
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com/v1"
+	anthropicVersion        = "2023-06-01"
+	anthropicMaxTokens      = 4096
+)
+
+// AnthropicProvider talks to Anthropic's /v1/messages endpoint.
+type AnthropicProvider struct {
+	baseURL string
+	apiKey  string
+}
+
+// NewAnthropic constructs an AnthropicProvider from cfg.
+func NewAnthropic(cfg ProviderConfig) *AnthropicProvider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+	return &AnthropicProvider{baseURL: baseURL, apiKey: cfg.APIKey}
+}
+
+// Chat does not yet support tools for this provider; tools is accepted for
+// interface compatibility and ignored.
+func (p *AnthropicProvider) Chat(ctx context.Context, model string, messages []Message, tools []Tool) (string, []ToolCall, error) {
+	type anthropicMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	// Anthropic takes the system prompt out-of-band, not as a message. There
+	// may be several system messages (an agent's persona plus each pinned
+	// file), so join them rather than keeping only the last.
+	var systemParts []string
+	var chatMessages []anthropicMessage
+	for _, m := range messages {
+		if m.Role == "system" {
+			systemParts = append(systemParts, m.Content)
+			continue
+		}
+		chatMessages = append(chatMessages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+	bodyBytes, err := json.Marshal(struct {
+		Model     string             `json:"model"`
+		System    string             `json:"system,omitempty"`
+		Messages  []anthropicMessage `json:"messages"`
+		MaxTokens int                `json:"max_tokens"`
+	}{Model: model, System: strings.Join(systemParts, "\n\n"), Messages: chatMessages, MaxTokens: anthropicMaxTokens})
+	if err != nil {
+		return "", nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/messages", bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.StatusCode != 200 {
+		return "", nil, fmt.Errorf("anthropic API error: %s", string(respBody))
+	}
+	var apiResp struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return "", nil, err
+	}
+	var text string
+	for _, block := range apiResp.Content {
+		text += block.Text
+	}
+	return text, nil, nil
+}
+
+// ChatStream has no true streaming support yet; it falls back to Chat and
+// delivers the whole reply as a single token.
+func (p *AnthropicProvider) ChatStream(ctx context.Context, model string, messages []Message, tools []Tool, onToken func(string)) (string, []ToolCall, error) {
+	text, calls, err := p.Chat(ctx, model, messages, tools)
+	if err != nil {
+		return "", nil, err
+	}
+	if onToken != nil {
+		onToken(text)
+	}
+	return text, calls, nil
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderConfig holds the per-provider settings read from config.yaml.
+type ProviderConfig struct {
+	BaseURL      string `yaml:"base_url"`
+	APIKey       string `yaml:"api_key"`
+	DefaultModel string `yaml:"default_model"`
+}
+
+// SearchConfig holds the settings for the /search command's RAG pipeline:
+// which search backend to query, how many results and chunks to use, and
+// how big a chunk is.
+type SearchConfig struct {
+	Provider    string `yaml:"provider"` // "wikipedia" (default), "duckduckgo", "searxng", or "brave"
+	ResultCount int    `yaml:"result_count"`
+	TopK        int    `yaml:"top_k"`
+	ChunkSize   int    `yaml:"chunk_size"` // approximate words per chunk
+	EmbedModel  string `yaml:"embed_model"`
+	SearxURL    string `yaml:"searx_url"`
+	BraveAPIKey string `yaml:"brave_api_key"`
+}
+
+// Config is the top-level shape of ~/.config/owllama/config.yaml.
+type Config struct {
+	DefaultProvider string                    `yaml:"default_provider"`
+	Providers       map[string]ProviderConfig `yaml:"providers"`
+	Search          SearchConfig              `yaml:"search"`
+}
+
+// LoadConfig reads and parses the user's owllama config file. A missing
+// file is not an error: callers get a zero-value Config and fall back to
+// the built-in provider defaults.
+func LoadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return &Config{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Get returns the configured settings for name, or a zero-value
+// ProviderConfig if the user hasn't configured that provider.
+func (c *Config) Get(name string) ProviderConfig {
+	if c == nil {
+		return ProviderConfig{}
+	}
+	return c.Providers[name]
+}
+
+func configPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "owllama", "config.yaml"), nil
+}
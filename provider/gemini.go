@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const defaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// GeminiProvider talks to Google's Generative Language API.
+type GeminiProvider struct {
+	baseURL string
+	apiKey  string
+}
+
+// NewGemini constructs a GeminiProvider from cfg.
+func NewGemini(cfg ProviderConfig) *GeminiProvider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultGeminiBaseURL
+	}
+	return &GeminiProvider{baseURL: baseURL, apiKey: cfg.APIKey}
+}
+
+// Chat does not yet support tools for this provider; tools is accepted for
+// interface compatibility and ignored.
+func (p *GeminiProvider) Chat(ctx context.Context, model string, messages []Message, tools []Tool) (string, []ToolCall, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Role  string `json:"role"`
+		Parts []part `json:"parts"`
+	}
+	// Gemini takes the system prompt out-of-band, not as a content entry;
+	// contents may only hold "user"/"model" roles. There may be several
+	// system messages (an agent's persona plus each pinned file), so join
+	// them rather than keeping only the last.
+	var systemParts []string
+	var contents []content
+	for _, m := range messages {
+		if m.Role == "system" {
+			systemParts = append(systemParts, m.Content)
+			continue
+		}
+		role := m.Role
+		if role == "assistant" {
+			role = "model"
+		}
+		contents = append(contents, content{Role: role, Parts: []part{{Text: m.Content}}})
+	}
+	var systemInstruction *content
+	if len(systemParts) > 0 {
+		systemInstruction = &content{Parts: []part{{Text: strings.Join(systemParts, "\n\n")}}}
+	}
+	bodyBytes, err := json.Marshal(struct {
+		Contents          []content `json:"contents"`
+		SystemInstruction *content  `json:"systemInstruction,omitempty"`
+	}{Contents: contents, SystemInstruction: systemInstruction})
+	if err != nil {
+		return "", nil, err
+	}
+	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", p.baseURL, model, p.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.StatusCode != 200 {
+		return "", nil, fmt.Errorf("gemini API error: %s", string(respBody))
+	}
+	var apiResp struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return "", nil, err
+	}
+	if len(apiResp.Candidates) == 0 {
+		return "", nil, fmt.Errorf("gemini API returned no candidates")
+	}
+	var text string
+	for _, part := range apiResp.Candidates[0].Content.Parts {
+		text += part.Text
+	}
+	return text, nil, nil
+}
+
+// ChatStream has no true streaming support yet; it falls back to Chat and
+// delivers the whole reply as a single token.
+func (p *GeminiProvider) ChatStream(ctx context.Context, model string, messages []Message, tools []Tool, onToken func(string)) (string, []ToolCall, error) {
+	text, calls, err := p.Chat(ctx, model, messages, tools)
+	if err != nil {
+		return "", nil, err
+	}
+	if onToken != nil {
+		onToken(text)
+	}
+	return text, calls, nil
+}
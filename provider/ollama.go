@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const defaultOllamaBaseURL = "http://localhost:11434"
+
+// OllamaProvider talks to a local (or remote) Ollama server's /api/chat.
+type OllamaProvider struct {
+	baseURL string
+}
+
+// NewOllama constructs an OllamaProvider from cfg, falling back to the
+// default local Ollama server when BaseURL is unset.
+func NewOllama(cfg ProviderConfig) *OllamaProvider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+	return &OllamaProvider{baseURL: baseURL}
+}
+
+func (p *OllamaProvider) Chat(ctx context.Context, model string, messages []Message, tools []Tool) (string, []ToolCall, error) {
+	return p.ChatStream(ctx, model, messages, tools, nil)
+}
+
+func (p *OllamaProvider) ChatStream(ctx context.Context, model string, messages []Message, tools []Tool, onToken func(string)) (string, []ToolCall, error) {
+	bodyMap := struct {
+		Model    string       `json:"model"`
+		Messages []Message    `json:"messages"`
+		Tools    []ollamaTool `json:"tools,omitempty"`
+	}{Model: model, Messages: messages, Tools: toOllamaTools(tools)}
+	bodyBytes, err := json.Marshal(bodyMap)
+	if err != nil {
+		return "", nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", nil, fmt.Errorf("ollama API error: %s", string(respBody))
+	}
+
+	var responseText string
+	var calls []ToolCall
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var apiResp struct {
+			Message struct {
+				Role      string `json:"role"`
+				Content   string `json:"content"`
+				ToolCalls []struct {
+					Function struct {
+						Name      string         `json:"name"`
+						Arguments map[string]any `json:"arguments"`
+					} `json:"function"`
+				} `json:"tool_calls"`
+			} `json:"message"`
+			Done bool `json:"done"`
+		}
+		if err := json.Unmarshal([]byte(line), &apiResp); err != nil {
+			continue
+		}
+		if apiResp.Message.Role == "assistant" && apiResp.Message.Content != "" {
+			responseText += apiResp.Message.Content
+			if onToken != nil {
+				onToken(apiResp.Message.Content)
+			}
+		}
+		for _, tc := range apiResp.Message.ToolCalls {
+			calls = append(calls, ToolCall{Name: tc.Function.Name, Arguments: tc.Function.Arguments})
+		}
+		if apiResp.Done {
+			break
+		}
+	}
+	return responseText, calls, scanner.Err()
+}
+
+// ollamaTool is the {"type": "function", "function": {...}} shape Ollama's
+// /api/chat expects in its "tools" field.
+type ollamaTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Parameters  map[string]any `json:"parameters"`
+	} `json:"function"`
+}
+
+func toOllamaTools(tools []Tool) []ollamaTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]ollamaTool, len(tools))
+	for i, t := range tools {
+		out[i].Type = "function"
+		out[i].Function.Name = t.Name
+		out[i].Function.Description = t.Description
+		out[i].Function.Parameters = t.Parameters
+	}
+	return out
+}
+
+// MarshalJSON lets Message be sent using Ollama's {"role", "content"} shape.
+func (m Message) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}{Role: m.Role, Content: m.Content})
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIProvider talks to OpenAI's (or an OpenAI-compatible) chat
+// completions endpoint.
+type OpenAIProvider struct {
+	baseURL string
+	apiKey  string
+}
+
+// NewOpenAI constructs an OpenAIProvider from cfg.
+func NewOpenAI(cfg ProviderConfig) *OpenAIProvider {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	return &OpenAIProvider{baseURL: baseURL, apiKey: cfg.APIKey}
+}
+
+// Chat does not yet support tools for this provider; tools is accepted for
+// interface compatibility and ignored.
+func (p *OpenAIProvider) Chat(ctx context.Context, model string, messages []Message, tools []Tool) (string, []ToolCall, error) {
+	type oaiMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	oaiMessages := make([]oaiMessage, len(messages))
+	for i, m := range messages {
+		oaiMessages[i] = oaiMessage{Role: m.Role, Content: m.Content}
+	}
+	bodyBytes, err := json.Marshal(struct {
+		Model    string       `json:"model"`
+		Messages []oaiMessage `json:"messages"`
+	}{Model: model, Messages: oaiMessages})
+	if err != nil {
+		return "", nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.StatusCode != 200 {
+		return "", nil, fmt.Errorf("openai API error: %s", string(respBody))
+	}
+	var apiResp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return "", nil, err
+	}
+	if len(apiResp.Choices) == 0 {
+		return "", nil, fmt.Errorf("openai API returned no choices")
+	}
+	return apiResp.Choices[0].Message.Content, nil, nil
+}
+
+// ChatStream has no true streaming support yet; it falls back to Chat and
+// delivers the whole reply as a single token.
+func (p *OpenAIProvider) ChatStream(ctx context.Context, model string, messages []Message, tools []Tool, onToken func(string)) (string, []ToolCall, error) {
+	text, calls, err := p.Chat(ctx, model, messages, tools)
+	if err != nil {
+		return "", nil, err
+	}
+	if onToken != nil {
+		onToken(text)
+	}
+	return text, calls, nil
+}
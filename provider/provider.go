@@ -0,0 +1,83 @@
+// Package provider abstracts chat-completion backends (Ollama, OpenAI,
+// Anthropic, Google Gemini) behind a single interface so the rest of
+// owllama doesn't need to know which one it's talking to.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Message is a single chat turn, independent of any provider's wire format.
+type Message struct {
+	Role    string
+	Content string
+}
+
+// Tool describes a callable function a model may invoke, independent of
+// any provider's wire format.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]any // JSON schema for the tool's arguments
+}
+
+// ToolCall is a model's request to invoke one of the offered Tools.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments map[string]any
+}
+
+// ChatCompletionProvider is implemented by every supported backend.
+type ChatCompletionProvider interface {
+	// Chat sends messages (and, if the backend supports it, tools) to
+	// model and returns the assistant's reply. If the model wants to
+	// invoke a tool instead of replying, calls is non-empty and text is
+	// the backend's (possibly empty) accompanying text.
+	Chat(ctx context.Context, model string, messages []Message, tools []Tool) (text string, calls []ToolCall, err error)
+
+	// ChatStream behaves like Chat but invokes onToken as each chunk of
+	// the reply's text arrives. The full reply is returned once the
+	// stream completes.
+	ChatStream(ctx context.Context, model string, messages []Message, tools []Tool, onToken func(string)) (text string, calls []ToolCall, err error)
+}
+
+// New constructs the provider registered under name, configured with cfg.
+func New(name string, cfg ProviderConfig) (ChatCompletionProvider, error) {
+	switch name {
+	case "ollama":
+		return NewOllama(cfg), nil
+	case "openai":
+		return NewOpenAI(cfg), nil
+	case "anthropic":
+		return NewAnthropic(cfg), nil
+	case "gemini", "google":
+		return NewGemini(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+}
+
+// knownProviderNames are the prefixes ParseModelString will recognize. Kept
+// in sync with the cases New switches on.
+var knownProviderNames = map[string]bool{
+	"ollama":    true,
+	"openai":    true,
+	"anthropic": true,
+	"gemini":    true,
+	"google":    true,
+}
+
+// ParseModelString splits a "provider:model" string, e.g. "openai:gpt-4o",
+// into its provider name and model name. The prefix before the first ":" is
+// only treated as a provider name if it's one New recognizes; otherwise s is
+// returned unchanged as model, since Ollama's own model names are
+// "name:tag" (e.g. "gemma3:12b") and must not be split on the colon.
+func ParseModelString(s string) (providerName, model string) {
+	if i := strings.Index(s, ":"); i > 0 && knownProviderNames[s[:i]] {
+		return s[:i], s[i+1:]
+	}
+	return "", s
+}
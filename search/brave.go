@@ -0,0 +1,60 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// BraveSearcher queries the Brave Search API.
+type BraveSearcher struct {
+	apiKey string
+}
+
+// NewBrave returns a BraveSearcher authenticated with apiKey.
+func NewBrave(apiKey string) *BraveSearcher {
+	return &BraveSearcher{apiKey: apiKey}
+}
+
+func (s *BraveSearcher) Search(ctx context.Context, query string, n int) ([]Result, error) {
+	if s.apiKey == "" {
+		return nil, fmt.Errorf("brave: no API key configured (search.brave_api_key)")
+	}
+	reqURL := "https://api.search.brave.com/res/v1/web/search?q=" + url.QueryEscape(query)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Subscription-Token", s.apiKey)
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("brave search API error: %s", resp.Status)
+	}
+	var data struct {
+		Web struct {
+			Results []struct {
+				URL         string `json:"url"`
+				Title       string `json:"title"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	results := make([]Result, 0, n)
+	for _, r := range data.Web.Results {
+		if len(results) >= n {
+			break
+		}
+		results = append(results, Result{URL: r.URL, Title: r.Title, Snippet: r.Description})
+	}
+	return results, nil
+}
@@ -0,0 +1,25 @@
+package search
+
+import "strings"
+
+// ChunkText splits text into chunks of roughly size words (used as a
+// simple stand-in for tokens), breaking on whitespace so chunks don't
+// split mid-word.
+func ChunkText(text string, size int) []string {
+	if size <= 0 {
+		size = 500
+	}
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	var chunks []string
+	for i := 0; i < len(words); i += size {
+		end := i + size
+		if end > len(words) {
+			end = len(words)
+		}
+		chunks = append(chunks, strings.Join(words[i:end], " "))
+	}
+	return chunks
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// DuckDuckGoSearcher scrapes DuckDuckGo's HTML-only results page (the same
+// one used by browsers with JavaScript disabled), since DuckDuckGo has no
+// public JSON search API.
+type DuckDuckGoSearcher struct{}
+
+// NewDuckDuckGo returns a DuckDuckGoSearcher.
+func NewDuckDuckGo() *DuckDuckGoSearcher {
+	return &DuckDuckGoSearcher{}
+}
+
+func (s *DuckDuckGoSearcher) Search(ctx context.Context, query string, n int) ([]Result, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://html.duckduckgo.com/html/?q="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "owllama/1.0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("duckduckgo returned %s", resp.Status)
+	}
+	doc, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	var walk func(*html.Node)
+	var pending *Result
+	walk = func(node *html.Node) {
+		if len(results) >= n {
+			return
+		}
+		if node.Type == html.ElementNode && node.Data == "a" && hasClass(node, "result__a") {
+			pending = &Result{URL: attr(node, "href"), Title: strings.TrimSpace(textContent(node))}
+		}
+		if node.Type == html.ElementNode && node.Data == "a" && hasClass(node, "result__snippet") && pending != nil {
+			pending.Snippet = strings.TrimSpace(textContent(node))
+			results = append(results, *pending)
+			pending = nil
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return results, nil
+}
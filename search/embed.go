@@ -0,0 +1,71 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+)
+
+// Embedder computes text embeddings via an Ollama server's /api/embeddings
+// endpoint.
+type Embedder struct {
+	baseURL string
+	model   string
+}
+
+// NewEmbedder returns an Embedder that calls baseURL using model.
+func NewEmbedder(baseURL, model string) *Embedder {
+	return &Embedder{baseURL: baseURL, model: model}
+}
+
+// Embed returns text's embedding vector.
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	body, err := json.Marshal(struct {
+		Model  string `json:"model"`
+		Prompt string `json:"prompt"`
+	}{Model: e.model, Prompt: text})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/api/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("ollama embeddings API error: %s", resp.Status)
+	}
+	var data struct {
+		Embedding []float64 `json:"embedding"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data.Embedding, nil
+}
+
+// CosineSimilarity returns the cosine similarity of a and b, or 0 if
+// either is empty or zero-length.
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,38 @@
+package search
+
+import "testing"
+
+func TestCosineSimilarityIdentical(t *testing.T) {
+	v := []float64{1, 2, 3}
+	got := CosineSimilarity(v, v)
+	if got < 0.999999 || got > 1.000001 {
+		t.Errorf("identical vectors: got %v, want ~1", got)
+	}
+}
+
+func TestCosineSimilarityOrthogonal(t *testing.T) {
+	got := CosineSimilarity([]float64{1, 0}, []float64{0, 1})
+	if got != 0 {
+		t.Errorf("orthogonal vectors: got %v, want 0", got)
+	}
+}
+
+func TestCosineSimilarityOpposite(t *testing.T) {
+	got := CosineSimilarity([]float64{1, 0}, []float64{-1, 0})
+	if got != -1 {
+		t.Errorf("opposite vectors: got %v, want -1", got)
+	}
+}
+
+func TestCosineSimilarityMismatchedOrEmpty(t *testing.T) {
+	cases := [][2][]float64{
+		{nil, nil},
+		{{1, 2}, {1, 2, 3}},
+		{{0, 0}, {1, 1}},
+	}
+	for _, c := range cases {
+		if got := CosineSimilarity(c[0], c[1]); got != 0 {
+			t.Errorf("CosineSimilarity(%v, %v) = %v, want 0", c[0], c[1], got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const fetchPageMaxBytes = 2 << 20 // 2 MiB, enough for an article's markup
+
+// FetchText downloads the page at url and returns its visible text, with
+// <script> and <style> content stripped.
+func FetchText(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	doc, err := html.Parse(io.LimitReader(resp.Body, fetchPageMaxBytes))
+	if err != nil {
+		return "", err
+	}
+	return extractText(doc), nil
+}
+
+// extractText walks doc depth-first, concatenating text nodes and skipping
+// the contents of <script> and <style> elements.
+func extractText(node *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+			return
+		}
+		if n.Type == html.TextNode {
+			text := strings.TrimSpace(n.Data)
+			if text != "" {
+				b.WriteString(text)
+				b.WriteString(" ")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(node)
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package search
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// attr returns node's value for the given attribute name, or "".
+func attr(node *html.Node, name string) string {
+	for _, a := range node.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasClass reports whether node's class attribute includes class.
+func hasClass(node *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(node, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent concatenates all text nodes under node, depth-first.
+func textContent(node *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(node)
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JasonCRodriguez/owllama/provider"
+)
+
+const (
+	defaultResultCount = 5
+	defaultTopK        = 3
+	defaultChunkSize   = 500
+	defaultEmbedModel  = "nomic-embed-text"
+)
+
+// rankedChunk is one chunk of page text scored against the query.
+type rankedChunk struct {
+	source string
+	text   string
+	score  float64
+}
+
+// Run queries cfg's search backend for query, fetches and chunks the top
+// results, ranks every chunk against query by embedding similarity, and
+// returns the best matches formatted as a single block of context, each
+// chunk prefixed with its source URL, ready to inject as a role:system
+// message. ollamaBaseURL is the Ollama server used to compute embeddings.
+func Run(ctx context.Context, query string, cfg provider.SearchConfig, ollamaBaseURL string) (string, error) {
+	searcher, err := New(cfg)
+	if err != nil {
+		return "", err
+	}
+	n := cfg.ResultCount
+	if n <= 0 {
+		n = defaultResultCount
+	}
+	k := cfg.TopK
+	if k <= 0 {
+		k = defaultTopK
+	}
+	chunkSize := cfg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	embedModel := cfg.EmbedModel
+	if embedModel == "" {
+		embedModel = defaultEmbedModel
+	}
+
+	results, err := searcher.Search(ctx, query, n)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "No relevant information found.", nil
+	}
+
+	embedder := NewEmbedder(ollamaBaseURL, embedModel)
+	queryEmbedding, err := embedder.Embed(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("embedding query: %w", err)
+	}
+
+	var ranked []rankedChunk
+	for _, r := range results {
+		text := r.Snippet
+		if page, err := FetchText(ctx, r.URL); err == nil && page != "" {
+			text = page
+		}
+		for _, chunk := range ChunkText(text, chunkSize) {
+			embedding, err := embedder.Embed(ctx, chunk)
+			if err != nil {
+				continue // skip chunks the embedding server can't handle
+			}
+			ranked = append(ranked, rankedChunk{
+				source: r.URL,
+				text:   chunk,
+				score:  CosineSimilarity(queryEmbedding, embedding),
+			})
+		}
+	}
+	if len(ranked) == 0 {
+		return "No relevant information found.", nil
+	}
+
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	if len(ranked) > k {
+		ranked = ranked[:k]
+	}
+
+	var b strings.Builder
+	for _, c := range ranked {
+		fmt.Fprintf(&b, "Source: %s\n%s\n\n", c.source, c.text)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
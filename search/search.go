@@ -0,0 +1,42 @@
+// Package search grounds chat replies in real web content: it queries a
+// pluggable search backend, fetches and chunks the resulting pages, ranks
+// the chunks against the query by embedding similarity, and returns the
+// top matches formatted as context ready to inject into a chat.
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JasonCRodriguez/owllama/provider"
+)
+
+// Result is a single hit from a Searcher.
+type Result struct {
+	URL     string
+	Title   string
+	Snippet string
+}
+
+// Searcher looks up a query against a web search backend and returns up to
+// n results.
+type Searcher interface {
+	Search(ctx context.Context, query string, n int) ([]Result, error)
+}
+
+// New constructs the Searcher registered under cfg.Provider, defaulting to
+// Wikipedia when unset.
+func New(cfg provider.SearchConfig) (Searcher, error) {
+	switch cfg.Provider {
+	case "", "wikipedia":
+		return NewWikipedia(), nil
+	case "duckduckgo":
+		return NewDuckDuckGo(), nil
+	case "searxng":
+		return NewSearxNG(cfg.SearxURL), nil
+	case "brave":
+		return NewBrave(cfg.BraveAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unknown search provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SearxNGSearcher queries a self-hosted SearxNG instance's JSON API.
+type SearxNGSearcher struct {
+	baseURL string
+}
+
+// NewSearxNG returns a SearxNGSearcher for the given instance URL, e.g.
+// "https://searx.example.org".
+func NewSearxNG(baseURL string) *SearxNGSearcher {
+	return &SearxNGSearcher{baseURL: baseURL}
+}
+
+func (s *SearxNGSearcher) Search(ctx context.Context, query string, n int) ([]Result, error) {
+	if s.baseURL == "" {
+		return nil, fmt.Errorf("searxng: no instance URL configured (search.searx_url)")
+	}
+	reqURL := s.baseURL + "/search?format=json&q=" + url.QueryEscape(query)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("searxng returned %s", resp.Status)
+	}
+	var data struct {
+		Results []struct {
+			URL     string `json:"url"`
+			Title   string `json:"title"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	results := make([]Result, 0, n)
+	for _, r := range data.Results {
+		if len(results) >= n {
+			break
+		}
+		results = append(results, Result{URL: r.URL, Title: r.Title, Snippet: r.Content})
+	}
+	return results, nil
+}
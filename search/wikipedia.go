@@ -0,0 +1,62 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WikipediaSearcher looks up a single page via Wikipedia's REST summary
+// API. It ignores n: the summary endpoint takes a page title rather than
+// a query, so it can only ever return one hit.
+type WikipediaSearcher struct{}
+
+// NewWikipedia returns a WikipediaSearcher.
+func NewWikipedia() *WikipediaSearcher {
+	return &WikipediaSearcher{}
+}
+
+func (s *WikipediaSearcher) Search(ctx context.Context, query string, n int) ([]Result, error) {
+	apiURL := "https://en.wikipedia.org/api/rest_v1/page/summary/" + urlQueryEscapeWiki(query)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == 404 {
+		return nil, nil
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("wikipedia API error: %s", resp.Status)
+	}
+	var data struct {
+		Title      string `json:"title"`
+		Extract    string `json:"extract"`
+		ContentURL struct {
+			Desktop struct {
+				Page string `json:"page"`
+			} `json:"desktop"`
+		} `json:"content_urls"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	if data.Extract == "" {
+		return nil, nil
+	}
+	return []Result{{URL: data.ContentURL.Desktop.Page, Title: data.Title, Snippet: data.Extract}}, nil
+}
+
+// urlQueryEscapeWiki escapes a string for use in a Wikipedia API URL.
+func urlQueryEscapeWiki(s string) string {
+	s = strings.ReplaceAll(s, " ", "_")
+	s = strings.ReplaceAll(s, "\n", "")
+	s = strings.ReplaceAll(s, "\r", "")
+	return s
+}
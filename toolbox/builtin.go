@@ -0,0 +1,263 @@
+package toolbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const webFetchMaxBytes = 1 << 20 // 1 MiB, enough for a page's text without risking a runaway download
+
+// Default returns a Toolbox with owllama's built-in tools registered:
+// read_file, modify_file, dir_tree, shell_exec, and web_fetch. shellAllowlist
+// restricts shell_exec to those command names; a nil/empty allowlist allows
+// none.
+func Default(shellAllowlist []string) *Toolbox {
+	tb := New()
+	tb.Register(readFileTool())
+	tb.Register(modifyFileTool())
+	tb.Register(dirTreeTool())
+	tb.Register(shellExecTool(shellAllowlist))
+	tb.Register(webFetchTool())
+	return tb
+}
+
+func readFileTool() ToolSpec {
+	return ToolSpec{
+		Name:        "read_file",
+		Description: "Read the contents of a file at the given path.",
+		Parameters: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"path": map[string]any{"type": "string"}},
+			"required":   []string{"path"},
+		},
+		Impl: func(_ context.Context, args map[string]any) (string, error) {
+			path, ok := args["path"].(string)
+			if !ok || path == "" {
+				return "", fmt.Errorf("read_file: missing \"path\" argument")
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+	}
+}
+
+func modifyFileTool() ToolSpec {
+	return ToolSpec{
+		Name:        "modify_file",
+		Description: "Apply a unified diff to a file at the given path.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{"type": "string"},
+				"diff": map[string]any{"type": "string", "description": "a unified diff (as produced by `diff -u`) of the changes to apply"},
+			},
+			"required": []string{"path", "diff"},
+		},
+		Impl: func(_ context.Context, args map[string]any) (string, error) {
+			path, _ := args["path"].(string)
+			diff, _ := args["diff"].(string)
+			if path == "" || diff == "" {
+				return "", fmt.Errorf("modify_file: missing \"path\" or \"diff\" argument")
+			}
+			original, err := os.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			patched, err := applyUnifiedDiff(string(original), diff)
+			if err != nil {
+				return "", fmt.Errorf("modify_file: %w", err)
+			}
+			if err := os.WriteFile(path, []byte(patched), 0644); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("applied diff to %s", path), nil
+		},
+	}
+}
+
+func dirTreeTool() ToolSpec {
+	return ToolSpec{
+		Name:        "dir_tree",
+		Description: "List the contents of a directory up to a depth limit, returned as JSON.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":  map[string]any{"type": "string"},
+				"depth": map[string]any{"type": "integer", "description": "how many directory levels to descend (default 2)"},
+			},
+			"required": []string{"path"},
+		},
+		Impl: func(_ context.Context, args map[string]any) (string, error) {
+			path, _ := args["path"].(string)
+			if path == "" {
+				return "", fmt.Errorf("dir_tree: missing \"path\" argument")
+			}
+			depth := 2
+			if d, ok := args["depth"].(float64); ok { // JSON numbers decode as float64
+				depth = int(d)
+			}
+			tree, err := buildDirTree(path, depth)
+			if err != nil {
+				return "", err
+			}
+			out, err := json.Marshal(tree)
+			if err != nil {
+				return "", err
+			}
+			return string(out), nil
+		},
+	}
+}
+
+// dirEntry is one node in a dir_tree result.
+type dirEntry struct {
+	Name     string     `json:"name"`
+	IsDir    bool       `json:"is_dir"`
+	Children []dirEntry `json:"children,omitempty"`
+}
+
+func buildDirTree(path string, depth int) (dirEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return dirEntry{}, err
+	}
+	node := dirEntry{Name: info.Name(), IsDir: info.IsDir()}
+	if !info.IsDir() || depth <= 0 {
+		return node, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return dirEntry{}, err
+	}
+	for _, e := range entries {
+		child, err := buildDirTree(filepath.Join(path, e.Name()), depth-1)
+		if err != nil {
+			return dirEntry{}, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+func shellExecTool(allowlist []string) ToolSpec {
+	allowed := make(map[string]bool, len(allowlist))
+	for _, c := range allowlist {
+		allowed[c] = true
+	}
+	return ToolSpec{
+		Name:        "shell_exec",
+		Description: "Run a shell command and return its combined output. Only allowlisted commands may be run.",
+		Parameters: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"command": map[string]any{"type": "string"}},
+			"required":   []string{"command"},
+		},
+		Impl: func(ctx context.Context, args map[string]any) (string, error) {
+			command, _ := args["command"].(string)
+			if command == "" {
+				return "", fmt.Errorf("shell_exec: missing \"command\" argument")
+			}
+			argv, err := splitCommandWords(command)
+			if err != nil {
+				return "", fmt.Errorf("shell_exec: %w", err)
+			}
+			if len(argv) == 0 || !allowed[argv[0]] {
+				return "", fmt.Errorf("shell_exec: command %q is not allowlisted", command)
+			}
+			// exec.Command runs argv[0] directly with no shell in between, so
+			// shell metacharacters in later arguments (;, &&, |, `...`, $(...))
+			// are inert rather than a way to smuggle past the allowlist.
+			cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				return string(out), fmt.Errorf("shell_exec: %w", err)
+			}
+			return string(out), nil
+		},
+	}
+}
+
+// splitCommandWords splits command into argv the way a shell would for
+// whitespace and simple single/double-quoted strings, without invoking a
+// shell or interpreting any other shell syntax (globs, substitutions,
+// redirections, etc. are all left as literal characters).
+func splitCommandWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %q quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+func webFetchTool() ToolSpec {
+	return ToolSpec{
+		Name:        "web_fetch",
+		Description: "Fetch a URL over HTTP(S) and return its body as text.",
+		Parameters: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"url": map[string]any{"type": "string"}},
+			"required":   []string{"url"},
+		},
+		Impl: func(ctx context.Context, args map[string]any) (string, error) {
+			url, _ := args["url"].(string)
+			if url == "" {
+				return "", fmt.Errorf("web_fetch: missing \"url\" argument")
+			}
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return "", err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return "", err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != 200 {
+				return "", fmt.Errorf("web_fetch: %s returned %s", url, resp.Status)
+			}
+			body, err := io.ReadAll(io.LimitReader(resp.Body, webFetchMaxBytes))
+			if err != nil {
+				return "", err
+			}
+			return string(body), nil
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package toolbox
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+\d+(?:,\d+)? @@`)
+
+// hunk is one @@ ... @@ block of a unified diff.
+type hunk struct {
+	oldStart int
+	lines    []string // each prefixed with ' ', '-', or '+'
+}
+
+// applyUnifiedDiff applies a unified diff (as produced by `diff -u`) to
+// original and returns the patched text. It supports the single-file,
+// single-hunk-or-more case modify_file is meant for; it does not handle
+// renames, binary diffs, or the --- /+++ file headers beyond skipping them.
+func applyUnifiedDiff(original, diffText string) (string, error) {
+	hunks, err := parseHunks(diffText)
+	if err != nil {
+		return "", err
+	}
+	origLines := strings.Split(original, "\n")
+	var result []string
+	pos := 0 // 0-based index into origLines, how far we've consumed
+
+	for _, h := range hunks {
+		start := h.oldStart - 1
+		if start < pos || start > len(origLines) {
+			return "", fmt.Errorf("hunk starting at line %d is out of range", h.oldStart)
+		}
+		result = append(result, origLines[pos:start]...)
+		pos = start
+		for _, line := range h.lines {
+			text := line[1:]
+			switch line[0] {
+			case ' ':
+				if pos >= len(origLines) || origLines[pos] != text {
+					return "", fmt.Errorf("context line %q does not match file at line %d", text, pos+1)
+				}
+				result = append(result, origLines[pos])
+				pos++
+			case '-':
+				if pos >= len(origLines) || origLines[pos] != text {
+					return "", fmt.Errorf("deleted line %q does not match file at line %d", text, pos+1)
+				}
+				pos++
+			case '+':
+				result = append(result, text)
+			}
+		}
+	}
+	result = append(result, origLines[pos:]...)
+	return strings.Join(result, "\n"), nil
+}
+
+func parseHunks(diffText string) ([]hunk, error) {
+	var hunks []hunk
+	var current *hunk
+	for _, line := range strings.Split(diffText, "\n") {
+		if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "diff ") {
+			continue
+		}
+		if m := hunkHeaderRe.FindStringSubmatch(line); m != nil {
+			if current != nil {
+				hunks = append(hunks, *current)
+			}
+			var oldStart int
+			fmt.Sscanf(m[1], "%d", &oldStart)
+			current = &hunk{oldStart: oldStart}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case ' ', '-', '+':
+			current.lines = append(current.lines, line)
+		}
+	}
+	if current != nil {
+		hunks = append(hunks, *current)
+	}
+	if len(hunks) == 0 {
+		return nil, fmt.Errorf("no hunks found in diff")
+	}
+	return hunks, nil
+}
@@ -0,0 +1,46 @@
+package toolbox
+
+import "testing"
+
+func TestApplyUnifiedDiffSimpleReplace(t *testing.T) {
+	original := "line one\nline two\nline three\n"
+	diff := "--- a/file\n+++ b/file\n@@ -1,3 +1,3 @@\n line one\n-line two\n+line TWO\n line three\n"
+
+	got, err := applyUnifiedDiff(original, diff)
+	if err != nil {
+		t.Fatalf("applyUnifiedDiff: %v", err)
+	}
+	want := "line one\nline TWO\nline three\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestApplyUnifiedDiffInsertAndDelete(t *testing.T) {
+	original := "a\nb\nc\n"
+	diff := "@@ -1,3 +1,3 @@\n a\n-b\n+b2\n+b3\n c\n"
+
+	got, err := applyUnifiedDiff(original, diff)
+	if err != nil {
+		t.Fatalf("applyUnifiedDiff: %v", err)
+	}
+	want := "a\nb2\nb3\nc\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestApplyUnifiedDiffContextMismatch(t *testing.T) {
+	original := "a\nb\nc\n"
+	diff := "@@ -1,3 +1,3 @@\n a\n-not there\n+x\n c\n"
+
+	if _, err := applyUnifiedDiff(original, diff); err == nil {
+		t.Fatal("expected an error for a context line that doesn't match the file")
+	}
+}
+
+func TestApplyUnifiedDiffNoHunks(t *testing.T) {
+	if _, err := applyUnifiedDiff("a\nb\n", "not a diff"); err == nil {
+		t.Fatal("expected an error when the diff has no hunks")
+	}
+}
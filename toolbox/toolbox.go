@@ -0,0 +1,83 @@
+// Package toolbox defines the tools a model may call during a chat — what
+// each tool is named, the JSON schema of its arguments, and the Go function
+// that actually runs it — independent of which provider is asking.
+package toolbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JasonCRodriguez/owllama/provider"
+)
+
+// ToolSpec describes one callable tool.
+type ToolSpec struct {
+	Name        string
+	Description string
+	Parameters  map[string]any // JSON schema for the tool's arguments
+	Impl        func(ctx context.Context, args map[string]any) (string, error)
+}
+
+// Toolbox is a registry of available tools.
+type Toolbox struct {
+	specs map[string]ToolSpec
+}
+
+// New returns an empty Toolbox.
+func New() *Toolbox {
+	return &Toolbox{specs: make(map[string]ToolSpec)}
+}
+
+// Register adds spec to the toolbox, replacing any existing tool with the
+// same name.
+func (tb *Toolbox) Register(spec ToolSpec) {
+	tb.specs[spec.Name] = spec
+}
+
+// Get looks up a tool by name.
+func (tb *Toolbox) Get(name string) (ToolSpec, bool) {
+	spec, ok := tb.specs[name]
+	return spec, ok
+}
+
+// Filtered returns the tools whose name appears in allowed, in the order
+// they are listed in allowed. An empty allowed list means "no restriction":
+// every registered tool is returned, in no particular order.
+func (tb *Toolbox) Filtered(allowed []string) []ToolSpec {
+	if len(allowed) == 0 {
+		specs := make([]ToolSpec, 0, len(tb.specs))
+		for _, spec := range tb.specs {
+			specs = append(specs, spec)
+		}
+		return specs
+	}
+	var specs []ToolSpec
+	for _, name := range allowed {
+		if spec, ok := tb.specs[name]; ok {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// Call invokes the named tool with args.
+func (tb *Toolbox) Call(ctx context.Context, name string, args map[string]any) (string, error) {
+	spec, ok := tb.Get(name)
+	if !ok {
+		return "", fmt.Errorf("unknown tool %q", name)
+	}
+	return spec.Impl(ctx, args)
+}
+
+// ToProviderTools converts specs into the provider-agnostic Tool
+// descriptors a ChatCompletionProvider expects.
+func ToProviderTools(specs []ToolSpec) []provider.Tool {
+	if len(specs) == 0 {
+		return nil
+	}
+	tools := make([]provider.Tool, len(specs))
+	for i, s := range specs {
+		tools[i] = provider.Tool{Name: s.Name, Description: s.Description, Parameters: s.Parameters}
+	}
+	return tools
+}
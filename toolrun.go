@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/JasonCRodriguez/owllama/agents"
+	"github.com/JasonCRodriguez/owllama/historystore"
+	"github.com/JasonCRodriguez/owllama/provider"
+	"github.com/JasonCRodriguez/owllama/toolbox"
+)
+
+// runToolLoop sends messages to p, and whenever the model asks to call a
+// tool, confirms with the user, runs it through tb, and feeds the result
+// back as a role:tool message, repeating until the model returns a plain
+// reply. It returns that reply, the updated message history, a ChatMessage
+// per tool call/result pair for persisting to history, and whether the
+// reply was already streamed to stdout as it arrived (so the caller
+// shouldn't print it again).
+//
+// Each call is wrapped in its own Ctrl-C-cancelable context, so
+// interrupting a long generation ends just that generation rather than the
+// whole chat session; whatever text streamed before the interrupt is
+// still returned for the caller to save to history.
+func runToolLoop(ctx context.Context, p provider.ChatCompletionProvider, model string, messages []provider.Message, tb *toolbox.Toolbox, specs []toolbox.ToolSpec, reader *bufio.Reader, autoApprove *bool, agent *agents.Agent) (text string, updated []provider.Message, toolRecords []historystore.ChatMessage, streamed bool, err error) {
+	tools := toolbox.ToProviderTools(specs)
+	// qwen3's <think> blocks must be stripped from the complete reply
+	// before it's shown, so there's nothing to gain from streaming it
+	// token by token; buffer it silently instead.
+	live := !strings.Contains(model, "qwen3")
+	for {
+		reqCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		var onToken func(string)
+		if live {
+			onToken = func(tok string) { fmt.Print(tok) }
+		}
+		var calls []provider.ToolCall
+		text, calls, err = p.ChatStream(reqCtx, model, messages, tools, onToken)
+		stop()
+		if live && text != "" {
+			fmt.Println()
+		}
+		if err != nil {
+			// text may hold whatever streamed in before the interrupt.
+			return text, messages, toolRecords, live, err
+		}
+		if len(calls) == 0 {
+			return text, messages, toolRecords, live, nil
+		}
+		for _, call := range calls {
+			result := runOneToolCall(ctx, tb, reader, autoApprove, call, agent)
+			messages = append(messages, provider.Message{Role: "tool", Content: result})
+			toolRecords = append(toolRecords, historystore.ChatMessage{
+				Role:     "tool",
+				Content:  result,
+				ToolCall: &historystore.ToolCallRecord{Name: call.Name, Arguments: call.Arguments, Result: result},
+			})
+		}
+	}
+}
+
+// runOneToolCall confirms and, if approved, executes a single tool call,
+// returning the text to feed back to the model as the tool's result. If
+// agent is non-nil, calls to tools outside its whitelist are rejected
+// outright, regardless of user confirmation — the whitelist is the active
+// agent's access boundary, not a suggestion the model can talk its way
+// around.
+func runOneToolCall(ctx context.Context, tb *toolbox.Toolbox, reader *bufio.Reader, autoApprove *bool, call provider.ToolCall, agent *agents.Agent) string {
+	if agent != nil && !agent.CanUseTool(call.Name) {
+		return fmt.Sprintf("tool %q is not in this agent's allowed_tools", call.Name)
+	}
+
+	argsJSON, _ := json.Marshal(call.Arguments)
+	fmt.Printf("\033[1;33mTool call:\033[0m %s(%s)\n", call.Name, string(argsJSON))
+
+	if !*autoApprove {
+		fmt.Print("Run this tool? [y/N/always] ")
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		switch answer {
+		case "y", "yes":
+			// proceed
+		case "always", "a":
+			*autoApprove = true
+		default:
+			return "tool call declined by user"
+		}
+	}
+
+	result, err := tb.Call(ctx, call.Name, call.Arguments)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return result
+}